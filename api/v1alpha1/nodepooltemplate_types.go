@@ -0,0 +1,139 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodePoolTemplateSelector picks which teams/pods a template applies to.
+// A template matches a reconcile request when every criterion it sets holds:
+// TeamValues contains the pod's `provision-for-team` value, and LabelSelector
+// matches the pod's labels. Empty selectors never match and are only valid on
+// the cluster-wide default.
+type NodePoolTemplateSelector struct {
+	// TeamValues is the list of `provision-for-team` nodeSelector values this
+	// template applies to.
+	// +optional
+	TeamValues []string `json:"teamValues,omitempty"`
+
+	// LabelSelector additionally restricts matching to pods carrying these
+	// labels. When set alongside TeamValues, both must match.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// NodeClassReference names the NodeClass a rendered NodePool should use,
+// mirroring `spec.template.spec.nodeClassRef` on karpenter.sh/v1 NodePool.
+type NodeClassReference struct {
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+}
+
+// NodePoolRequirement mirrors a single entry of
+// `spec.template.spec.requirements` on karpenter.sh/v1 NodePool.
+type NodePoolRequirement struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values,omitempty"`
+}
+
+// NodePoolTaint mirrors a single entry of `spec.template.spec.taints`.
+type NodePoolTaint struct {
+	Key    string             `json:"key"`
+	Value  string             `json:"value"`
+	Effect corev1.TaintEffect `json:"effect"`
+}
+
+// DisruptionBudget mirrors a single entry of `spec.disruption.budgets`.
+type DisruptionBudget struct {
+	Nodes    string `json:"nodes"`
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// DisruptionPolicy mirrors `spec.disruption` on karpenter.sh/v1 NodePool.
+type DisruptionPolicy struct {
+	ConsolidationPolicy string             `json:"consolidationPolicy,omitempty"`
+	ConsolidateAfter    string             `json:"consolidateAfter,omitempty"`
+	Budgets             []DisruptionBudget `json:"budgets,omitempty"`
+}
+
+// NodePoolTemplateSpec declares the policy a matching NodePool should be
+// rendered with. Fields map 1:1 onto the karpenter.sh/v1 NodePool schema so
+// renderNodePool can render an unstructured NodePool directly.
+type NodePoolTemplateSpec struct {
+	// Selector decides which teams/pods this template applies to. Leave unset
+	// on the cluster-wide default template only.
+	// +optional
+	Selector NodePoolTemplateSelector `json:"selector,omitempty"`
+
+	// Priority breaks ties when multiple templates match; higher wins.
+	// +optional
+	// +kubebuilder:default=0
+	Priority int32 `json:"priority,omitempty"`
+
+	// Limits caps the aggregate resources the rendered NodePool may provision.
+	Limits corev1.ResourceList `json:"limits,omitempty"`
+
+	// Requirements constrains instance selection (capacity type, families,
+	// arch, zones, ...).
+	Requirements []NodePoolRequirement `json:"requirements,omitempty"`
+
+	// NodeClassRef names the NodeClass rendered NodePools should reference.
+	NodeClassRef NodeClassReference `json:"nodeClassRef"`
+
+	// Taints are applied to every node launched by the rendered NodePool, in
+	// addition to the access taint the controller always adds for the pod's
+	// primary constraint (the `provision-for-team` one when the pod has it).
+	// +optional
+	Taints []NodePoolTaint `json:"taints,omitempty"`
+
+	// Labels are applied to every node launched by the rendered NodePool.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// ExpireAfter mirrors `spec.template.spec.expireAfter`.
+	// +optional
+	ExpireAfter string `json:"expireAfter,omitempty"`
+
+	// Disruption mirrors `spec.disruption`.
+	// +optional
+	Disruption DisruptionPolicy `json:"disruption,omitempty"`
+}
+
+// NodePoolTemplateStatus surfaces validation results for a template.
+type NodePoolTemplateStatus struct {
+	// Conditions track validation state, e.g. "SchemaAvailable" for whether
+	// the karpenter.sh/v1 NodePool schema is currently served.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NodePoolTemplate declares a per-team or per-selector-value policy that
+// PodReconciler renders into a karpenter.sh/v1 NodePool when it needs to
+// provision capacity for a pod it has no existing match for.
+type NodePoolTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodePoolTemplateSpec   `json:"spec,omitempty"`
+	Status NodePoolTemplateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodePoolTemplateList contains a list of NodePoolTemplate.
+type NodePoolTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodePoolTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodePoolTemplate{}, &NodePoolTemplateList{})
+}
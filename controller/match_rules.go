@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// matchRulesConfigMapName/Namespace locate the ConfigMap operators can
+	// use to configure MatchRules without rebuilding the binary. It is read
+	// once at startup, so an edit takes effect on the next controller start.
+	// A missing ConfigMap is not an error: DefaultMatchRules is used instead.
+	matchRulesConfigMapName      = "nodepool-controller-match-rules"
+	matchRulesConfigMapNamespace = "nodepool-controller-system"
+
+	// matchRulesConfigMapKey is the ConfigMap data key holding a JSON-encoded
+	// []MatchRule.
+	matchRulesConfigMapKey = "matchRules.json"
+
+	// provisionForTeamKey is the nodeSelector/taint key the controller has
+	// always matched on; DefaultMatchRules preserves that behavior.
+	provisionForTeamKey = "provision-for-team"
+)
+
+// MatchRule declares one way a pod can request a NodePool: a set of pod
+// nodeSelector keys, required node affinity keys, and/or toleration keys to
+// inspect, mapped onto a canonical taint key on the NodePool side. An empty
+// TaintKey means the matched pod key is used verbatim as the taint key.
+type MatchRule struct {
+	// Name identifies this rule in logs; purely descriptive.
+	Name string `json:"name"`
+
+	// NodeSelectorKeys are pod spec.nodeSelector keys this rule inspects.
+	NodeSelectorKeys []string `json:"nodeSelectorKeys,omitempty"`
+
+	// NodeAffinityKeys are keys inspected in
+	// spec.affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution
+	// match expressions with operator "In".
+	NodeAffinityKeys []string `json:"nodeAffinityKeys,omitempty"`
+
+	// TolerationKeys are pod toleration keys this rule inspects.
+	TolerationKeys []string `json:"tolerationKeys,omitempty"`
+
+	// TaintKey is the canonical taint key a match under this rule is
+	// reported as. Defaults to the pod key that matched.
+	TaintKey string `json:"taintKey,omitempty"`
+}
+
+// PodConstraint is one canonicalized key/value a pod requires of the
+// NodePool that schedules it, after being mapped through a MatchRule.
+type PodConstraint struct {
+	Key   string
+	Value string
+}
+
+// DefaultMatchRules reproduces the controller's original behavior: match
+// solely on the 'provision-for-team' nodeSelector.
+func DefaultMatchRules() []MatchRule {
+	return []MatchRule{
+		{
+			Name:             "provision-for-team",
+			NodeSelectorKeys: []string{provisionForTeamKey},
+			TaintKey:         provisionForTeamKey,
+		},
+	}
+}
+
+// loadMatchRules reads MatchRules from the matchRulesConfigMapName ConfigMap
+// when present, otherwise returns DefaultMatchRules. A malformed ConfigMap is
+// treated as an error so operators notice a bad edit instead of silently
+// falling back.
+func loadMatchRules(ctx context.Context, c client.Reader) ([]MatchRule, error) {
+	var cm corev1.ConfigMap
+	key := client.ObjectKey{Namespace: matchRulesConfigMapNamespace, Name: matchRulesConfigMapName}
+	if err := c.Get(ctx, key, &cm); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return DefaultMatchRules(), nil
+		}
+		return nil, fmt.Errorf("failed to get MatchRules ConfigMap %s: %w", key, err)
+	}
+
+	raw, ok := cm.Data[matchRulesConfigMapKey]
+	if !ok {
+		return DefaultMatchRules(), nil
+	}
+
+	var rules []MatchRule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse %s key of ConfigMap %s: %w", matchRulesConfigMapKey, key, err)
+	}
+	if len(rules) == 0 {
+		return DefaultMatchRules(), nil
+	}
+
+	return rules, nil
+}
+
+// extractPodConstraints inspects a pod's nodeSelector, required node
+// affinity, and tolerations against every configured MatchRule, returning
+// the canonical constraints a matching NodePool must satisfy.
+func extractPodConstraints(pod *corev1.Pod, rules []MatchRule) []PodConstraint {
+	var constraints []PodConstraint
+	seen := map[string]bool{}
+
+	add := func(rule MatchRule, key, value string) {
+		taintKey := rule.TaintKey
+		if taintKey == "" {
+			taintKey = key
+		}
+		dedupeKey := taintKey + "=" + value
+		if seen[dedupeKey] {
+			return
+		}
+		seen[dedupeKey] = true
+		constraints = append(constraints, PodConstraint{Key: taintKey, Value: value})
+	}
+
+	for _, rule := range rules {
+		for _, key := range rule.NodeSelectorKeys {
+			if value, ok := pod.Spec.NodeSelector[key]; ok {
+				add(rule, key, value)
+			}
+		}
+
+		for _, key := range rule.NodeAffinityKeys {
+			if value, ok := requiredNodeAffinityValue(pod, key); ok {
+				add(rule, key, value)
+			}
+		}
+
+		for _, key := range rule.TolerationKeys {
+			if value, ok := tolerationValue(pod, key); ok {
+				add(rule, key, value)
+			}
+		}
+	}
+
+	return constraints
+}
+
+// requiredNodeAffinityValue returns the first "In" value for key among the
+// pod's requiredDuringSchedulingIgnoredDuringExecution match expressions.
+func requiredNodeAffinityValue(pod *corev1.Pod, key string) (string, bool) {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+		return "", false
+	}
+	required := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil {
+		return "", false
+	}
+
+	for _, term := range required.NodeSelectorTerms {
+		for _, expr := range term.MatchExpressions {
+			if expr.Key != key || expr.Operator != corev1.NodeSelectorOpIn || len(expr.Values) == 0 {
+				continue
+			}
+			return expr.Values[0], true
+		}
+	}
+
+	return "", false
+}
+
+// tolerationValue returns the value of the first toleration matching key.
+func tolerationValue(pod *corev1.Pod, key string) (string, bool) {
+	for _, t := range pod.Spec.Tolerations {
+		if t.Key == key {
+			return t.Value, true
+		}
+	}
+	return "", false
+}
+
+// primaryConstraint returns the 'provision-for-team' constraint if present,
+// otherwise the first extracted constraint. NodePool naming, the team-GC
+// annotation, and the NodePool's own access taint all key off this single
+// constraint, while matching itself considers every extracted constraint.
+func primaryConstraint(constraints []PodConstraint) (PodConstraint, bool) {
+	for _, c := range constraints {
+		if c.Key == provisionForTeamKey {
+			return c, true
+		}
+	}
+	if len(constraints) > 0 {
+		return constraints[0], true
+	}
+	return PodConstraint{}, false
+}
+
+// primaryConstraintValue returns the value of primaryConstraint, discarding
+// its key. NodePool naming and the team-GC annotation only ever compare on
+// this value, regardless of which MatchRule key produced it.
+func primaryConstraintValue(constraints []PodConstraint) (string, bool) {
+	c, ok := primaryConstraint(constraints)
+	return c.Value, ok
+}
+
+// teamConstraintValue returns the value of the 'provision-for-team'
+// constraint only, unlike primaryConstraintValue which falls back to an
+// arbitrary constraint. Used wherever a false positive on a non-team
+// constraint would be wrong, e.g. the nodePoolTeamIndexKey lookup (which
+// only ever contains team taint values) or the admission webhook's
+// template-permission check.
+func teamConstraintValue(constraints []PodConstraint) (string, bool) {
+	for _, c := range constraints {
+		if c.Key == provisionForTeamKey {
+			return c.Value, true
+		}
+	}
+	return "", false
+}
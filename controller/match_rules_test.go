@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"testing"
+
+	nodepoolv1alpha1 "github.com/TrungHQ-02/nodepool-controller/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestExtractPodConstraints_MultiKeyRules(t *testing.T) {
+	rules := []MatchRule{
+		{
+			Name:             "provision-for-team",
+			NodeSelectorKeys: []string{provisionForTeamKey},
+			TaintKey:         provisionForTeamKey,
+		},
+		{
+			Name:             "architecture",
+			NodeAffinityKeys: []string{"architecture"},
+		},
+		{
+			Name:           "capacity-type",
+			TolerationKeys: []string{"karpenter.sh/capacity-type"},
+			TaintKey:       "karpenter.sh/capacity-type",
+		},
+	}
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{provisionForTeamKey: "ml"},
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key: "architecture", Operator: corev1.NodeSelectorOpIn, Values: []string{"arm64"},
+							}},
+						}},
+					},
+				},
+			},
+			Tolerations: []corev1.Toleration{
+				{Key: "karpenter.sh/capacity-type", Operator: corev1.TolerationOpEqual, Value: "spot"},
+			},
+		},
+	}
+
+	constraints := extractPodConstraints(pod, rules)
+	if len(constraints) != 3 {
+		t.Fatalf("expected 3 constraints, got %d: %+v", len(constraints), constraints)
+	}
+
+	want := map[string]string{
+		provisionForTeamKey:          "ml",
+		"architecture":               "arm64",
+		"karpenter.sh/capacity-type": "spot",
+	}
+	for _, c := range constraints {
+		if want[c.Key] != c.Value {
+			t.Errorf("unexpected constraint %s=%s", c.Key, c.Value)
+		}
+		delete(want, c.Key)
+	}
+	if len(want) != 0 {
+		t.Errorf("missing constraints: %+v", want)
+	}
+}
+
+func TestExtractPodConstraints_NoMatchRuleKeysPresent(t *testing.T) {
+	pod := &corev1.Pod{}
+	constraints := extractPodConstraints(pod, DefaultMatchRules())
+	if len(constraints) != 0 {
+		t.Fatalf("expected no constraints for a pod matching no rule, got %+v", constraints)
+	}
+}
+
+func TestPrimaryConstraint_PrefersTeamOverFirstExtracted(t *testing.T) {
+	constraints := []PodConstraint{
+		{Key: "architecture", Value: "arm64"},
+		{Key: provisionForTeamKey, Value: "ml"},
+	}
+
+	got, ok := primaryConstraint(constraints)
+	if !ok || got.Key != provisionForTeamKey || got.Value != "ml" {
+		t.Fatalf("expected the team constraint to win regardless of position, got %+v (ok=%v)", got, ok)
+	}
+}
+
+func TestPrimaryConstraint_FallsBackToFirstWhenNoTeam(t *testing.T) {
+	constraints := []PodConstraint{
+		{Key: "architecture", Value: "arm64"},
+		{Key: "karpenter.sh/capacity-type", Value: "spot"},
+	}
+
+	got, ok := primaryConstraint(constraints)
+	if !ok || got.Key != "architecture" || got.Value != "arm64" {
+		t.Fatalf("expected the first extracted constraint as fallback, got %+v (ok=%v)", got, ok)
+	}
+}
+
+func TestPrimaryConstraint_EmptyConstraints(t *testing.T) {
+	if _, ok := primaryConstraint(nil); ok {
+		t.Fatalf("expected ok=false for no constraints")
+	}
+}
+
+func TestTeamConstraintValue_IgnoresNonTeamConstraints(t *testing.T) {
+	constraints := []PodConstraint{{Key: "architecture", Value: "arm64"}}
+
+	if _, ok := teamConstraintValue(constraints); ok {
+		t.Fatalf("expected teamConstraintValue to ignore a non-team-only constraint set")
+	}
+}
+
+func TestNodePoolSatisfiesConstraints_TaintAndRequirement(t *testing.T) {
+	tpl := &nodepoolv1alpha1.NodePoolTemplate{
+		Spec: nodepoolv1alpha1.NodePoolTemplateSpec{
+			NodeClassRef: nodepoolv1alpha1.NodeClassReference{Group: "karpenter.k8s.aws", Kind: "EC2NodeClass", Name: "custom"},
+		},
+	}
+	nodePool := renderNodePool(tpl, PodConstraint{Key: "architecture", Value: "arm64"})
+	if err := appendConstraintRequirements(nodePool, []PodConstraint{
+		{Key: "architecture", Value: "arm64"},
+		{Key: "karpenter.sh/capacity-type", Value: "spot"},
+	}, PodConstraint{Key: "architecture", Value: "arm64"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	satisfied := []PodConstraint{
+		{Key: "architecture", Value: "arm64"},
+		{Key: "karpenter.sh/capacity-type", Value: "spot"},
+	}
+	if !nodePoolSatisfiesConstraints(nodePool, satisfied) {
+		t.Fatalf("expected NodePool to satisfy both its taint and its requirement constraint")
+	}
+
+	unsatisfied := []PodConstraint{{Key: "architecture", Value: "amd64"}}
+	if nodePoolSatisfiesConstraints(nodePool, unsatisfied) {
+		t.Fatalf("expected NodePool not to satisfy a mismatched architecture constraint")
+	}
+}
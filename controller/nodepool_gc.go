@@ -0,0 +1,367 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// managedByAnnotation/managedByValue mark NodePools this controller
+	// created, so the GC reconciler never touches hand-authored NodePools.
+	managedByAnnotation = "nodepool.trunghq.io/managed-by"
+	managedByValue      = "nodepool-controller"
+
+	// teamAnnotation records the 'provision-for-team' value a managed
+	// NodePool was rendered for.
+	teamAnnotation = "nodepool.trunghq.io/team"
+
+	// protectAnnotation opts an individual managed NodePool out of GC.
+	protectAnnotation = "nodepool.trunghq.io/protect"
+
+	// lastReferencedAnnotation tracks the last time a pending or scheduled
+	// pod was observed referencing a managed NodePool's team.
+	lastReferencedAnnotation = "nodepool.trunghq.io/last-referenced"
+
+	// gcFinalizer blocks NodePool deletion until the delete pipeline (drain
+	// check -> cordon claimed nodes -> remove finalizer) has completed.
+	gcFinalizer = "nodepool.trunghq.io/gc"
+
+	// podNodeNameIndexKey indexes Pods by spec.nodeName so the drain check
+	// can look up pods on a node without a cluster-wide list per node.
+	podNodeNameIndexKey = "spec.nodeName"
+
+	// nodePoolLabel is the label karpenter.sh stamps on nodes it launches,
+	// naming the NodePool that claimed them.
+	nodePoolLabel = "karpenter.sh/nodepool"
+)
+
+// DefaultGCGracePeriod is used by NewNodePoolGCReconciler when no grace
+// period is configured.
+const DefaultGCGracePeriod = 24 * time.Hour
+
+// stampRefreshFraction caps how often Reconcile rewrites
+// lastReferencedAnnotation for an actively-referenced NodePool: at most once
+// per GracePeriod/stampRefreshFraction, so the write stays infrequent enough
+// not to turn the NodePool watch into a self-sustaining reconcile loop, while
+// still keeping the timestamp fresh relative to GracePeriod.
+const stampRefreshFraction = 10
+
+// NodePoolGCReconciler garbage-collects team NodePools that PodReconciler
+// created but that no pod has referenced for GracePeriod. It follows the
+// stamp-owner / finalizer / delete-pipeline shape of the Kubernetes generic
+// garbage collector and Kratix-style delete pipelines: drain check -> cordon
+// nodes claimed by the pool -> delete NodePool -> remove finalizer.
+type NodePoolGCReconciler struct {
+	client.Client
+	DynamicClient dynamic.Interface
+
+	// Resolver resolves the karpenter.sh NodePool GVR/GVK through discovery
+	// instead of a hardcoded version.
+	Resolver *NodePoolResolver
+
+	// MatchRules configures which pod nodeSelector/affinity/toleration keys
+	// are matched against NodePools. Loaded once at startup, same as
+	// PodReconciler, so teamHasReferencingPods recognizes a referencing pod
+	// regardless of which MatchRule its constraint came through.
+	MatchRules []MatchRule
+
+	// GracePeriod is how long a managed NodePool's team must go unreferenced
+	// by any pending/running pod before the NodePool is deleted.
+	GracePeriod time.Duration
+
+	// DryRun logs what the delete pipeline would do without cordoning nodes,
+	// deleting the NodePool, or removing its finalizer.
+	DryRun bool
+}
+
+// NewNodePoolGCReconciler builds a NodePoolGCReconciler. A non-positive
+// gracePeriod falls back to DefaultGCGracePeriod.
+func NewNodePoolGCReconciler(mgr ctrl.Manager, dynamicClient dynamic.Interface, resolver *NodePoolResolver, gracePeriod time.Duration, dryRun bool) (*NodePoolGCReconciler, error) {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultGCGracePeriod
+	}
+
+	matchRules, err := loadMatchRules(context.Background(), mgr.GetAPIReader())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load MatchRules: %w", err)
+	}
+
+	return &NodePoolGCReconciler{
+		Client:        mgr.GetClient(),
+		DynamicClient: dynamicClient,
+		Resolver:      resolver,
+		MatchRules:    matchRules,
+		GracePeriod:   gracePeriod,
+		DryRun:        dryRun,
+	}, nil
+}
+
+// Reconcile drives a single managed NodePool through the GC state machine:
+// stamp a finalizer, track whether its team is still referenced, and once
+// GracePeriod has elapsed with no references, run the delete pipeline.
+func (r *NodePoolGCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	nodePool := newNodePool(r.Resolver)
+	if err := r.Get(ctx, req.NamespacedName, nodePool); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get NodePool", "nodePool", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	if nodePool.GetAnnotations()[managedByAnnotation] != managedByValue {
+		return ctrl.Result{}, nil
+	}
+
+	if nodePool.GetDeletionTimestamp() != nil {
+		return r.runDeletePipeline(ctx, nodePool)
+	}
+
+	if !controllerutil.ContainsFinalizer(nodePool, gcFinalizer) {
+		controllerutil.AddFinalizer(nodePool, gcFinalizer)
+		if _, err := r.DynamicClient.Resource(r.Resolver.Resource()).Namespace("").Update(ctx, nodePool, metav1.UpdateOptions{}); err != nil {
+			logger.Error(err, "Failed to add GC finalizer", "nodePool", nodePool.GetName())
+			return ctrl.Result{}, err
+		}
+	}
+
+	if nodePool.GetAnnotations()[protectAnnotation] == "true" {
+		logger.Info("NodePool is protected from GC", "nodePool", nodePool.GetName())
+		return ctrl.Result{RequeueAfter: r.GracePeriod}, nil
+	}
+
+	team := nodePool.GetAnnotations()[teamAnnotation]
+	referenced, err := r.teamHasReferencingPods(ctx, team)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	lastReferenced, stamped := lastReferencedTime(nodePool)
+	if referenced {
+		// Only rewrite the annotation once it's gone stale. Stamping on every
+		// reconcile would, via SetupWithManager's unfiltered NodePool watch,
+		// turn every actively-referenced NodePool into a perpetual
+		// reconcile/write loop instead of a periodic grace-period check.
+		if stamped {
+			if age := time.Since(lastReferenced); age < r.stampRefreshInterval() {
+				return ctrl.Result{RequeueAfter: r.stampRefreshInterval() - age}, nil
+			}
+		}
+		return r.stampLastReferenced(ctx, nodePool)
+	}
+
+	if !stamped {
+		return r.stampLastReferenced(ctx, nodePool)
+	}
+
+	if elapsed := time.Since(lastReferenced); elapsed < r.GracePeriod {
+		return ctrl.Result{RequeueAfter: r.GracePeriod - elapsed}, nil
+	}
+
+	if r.DryRun {
+		logger.Info("Dry-run: would delete unreferenced NodePool", "nodePool", nodePool.GetName(), "team", team)
+		return ctrl.Result{RequeueAfter: r.GracePeriod}, nil
+	}
+
+	logger.Info("Grace period elapsed with no referencing pods, deleting NodePool", "nodePool", nodePool.GetName(), "team", team)
+	if err := r.DynamicClient.Resource(r.Resolver.Resource()).Namespace("").Delete(ctx, nodePool.GetName(), metav1.DeleteOptions{}); err != nil {
+		logger.Error(err, "Failed to delete NodePool", "nodePool", nodePool.GetName())
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// runDeletePipeline executes drain check -> cordon -> remove finalizer for a
+// managed NodePool that is already terminating.
+func (r *NodePoolGCReconciler) runDeletePipeline(ctx context.Context, nodePool *unstructured.Unstructured) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(nodePool, gcFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	nodes, err := r.nodesClaimedBy(ctx, nodePool.GetName())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	drained, err := r.nodesDrained(ctx, nodes)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !drained {
+		logger.Info("Waiting for nodes to drain before removing GC finalizer", "nodePool", nodePool.GetName())
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	if r.DryRun {
+		logger.Info("Dry-run: would cordon claimed nodes and remove GC finalizer", "nodePool", nodePool.GetName())
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.cordonNodes(ctx, nodes); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	controllerutil.RemoveFinalizer(nodePool, gcFinalizer)
+	if _, err := r.DynamicClient.Resource(r.Resolver.Resource()).Namespace("").Update(ctx, nodePool, metav1.UpdateOptions{}); err != nil {
+		logger.Error(err, "Failed to remove GC finalizer", "nodePool", nodePool.GetName())
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Removed GC finalizer, NodePool will be deleted", "nodePool", nodePool.GetName())
+	return ctrl.Result{}, nil
+}
+
+// stampRefreshInterval is the minimum age lastReferencedAnnotation must
+// reach before Reconcile rewrites it again for a referenced NodePool.
+func (r *NodePoolGCReconciler) stampRefreshInterval() time.Duration {
+	return r.GracePeriod / stampRefreshFraction
+}
+
+// teamHasReferencingPods reports whether any pending or running pod's
+// extracted MatchRule constraints give it the same primary (team)
+// constraint value as teamAnnotation records for this NodePool. Using
+// extractPodConstraints/primaryConstraintValue here, rather than a hardcoded
+// 'provision-for-team' nodeSelector check, matters because teamAnnotation
+// itself is stamped from primaryConstraintValue and so may have come from an
+// affinity or toleration constraint, or from a non-team MatchRule key.
+func (r *NodePoolGCReconciler) teamHasReferencingPods(ctx context.Context, team string) (bool, error) {
+	if team == "" {
+		return false, nil
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods); err != nil {
+		return false, fmt.Errorf("failed to list pods while checking NodePool references: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodPending && pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+
+		constraints := extractPodConstraints(&pod, r.MatchRules)
+		value, ok := primaryConstraintValue(constraints)
+		if ok && value == team {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// nodesClaimedBy lists the Nodes karpenter.sh launched for a NodePool.
+func (r *NodePoolGCReconciler) nodesClaimedBy(ctx context.Context, nodePoolName string) ([]corev1.Node, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, client.MatchingLabels{nodePoolLabel: nodePoolName}); err != nil {
+		return nil, fmt.Errorf("failed to list nodes claimed by NodePool %q: %w", nodePoolName, err)
+	}
+	return nodes.Items, nil
+}
+
+// nodesDrained reports whether every node only hosts DaemonSet pods or pods
+// already terminating.
+func (r *NodePoolGCReconciler) nodesDrained(ctx context.Context, nodes []corev1.Node) (bool, error) {
+	for _, node := range nodes {
+		var pods corev1.PodList
+		if err := r.List(ctx, &pods, client.MatchingFields{podNodeNameIndexKey: node.Name}); err != nil {
+			return false, fmt.Errorf("failed to list pods on node %q: %w", node.Name, err)
+		}
+
+		for _, pod := range pods.Items {
+			if pod.DeletionTimestamp != nil || isDaemonSetPod(&pod) {
+				continue
+			}
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// cordonNodes marks every given node unschedulable.
+func (r *NodePoolGCReconciler) cordonNodes(ctx context.Context, nodes []corev1.Node) error {
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Spec.Unschedulable {
+			continue
+		}
+		node.Spec.Unschedulable = true
+		if err := r.Update(ctx, node); err != nil {
+			return fmt.Errorf("failed to cordon node %q: %w", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// isDaemonSetPod reports whether a pod is owned by a DaemonSet.
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// stampLastReferenced records that a managed NodePool's team is currently
+// referenced, resetting its GC grace-period clock.
+func (r *NodePoolGCReconciler) stampLastReferenced(ctx context.Context, nodePool *unstructured.Unstructured) (ctrl.Result, error) {
+	annotations := nodePool.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[lastReferencedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	nodePool.SetAnnotations(annotations)
+
+	if _, err := r.DynamicClient.Resource(r.Resolver.Resource()).Namespace("").Update(ctx, nodePool, metav1.UpdateOptions{}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to stamp last-referenced annotation on NodePool %q: %w", nodePool.GetName(), err)
+	}
+
+	return ctrl.Result{RequeueAfter: r.GracePeriod}, nil
+}
+
+// lastReferencedTime reads the lastReferencedAnnotation off a NodePool.
+func lastReferencedTime(nodePool *unstructured.Unstructured) (time.Time, bool) {
+	value, ok := nodePool.GetAnnotations()[lastReferencedAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodePoolGCReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, podNodeNameIndexKey, func(obj client.Object) []string {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Spec.NodeName == "" {
+			return nil
+		}
+		return []string{pod.Spec.NodeName}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to index pods by node name: %w", err)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(newNodePool(r.Resolver)).
+		Complete(r)
+}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTeamHasReferencingPods(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to register corev1 scheme: %v", err)
+	}
+
+	referencing := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-pod", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeSelector: map[string]string{provisionForTeamKey: "ml"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	succeeded := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-pod-done", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeSelector: map[string]string{provisionForTeamKey: "ml"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+	}
+	otherTeam := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-pod", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeSelector: map[string]string{provisionForTeamKey: "web"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	r := &NodePoolGCReconciler{
+		Client:     fake.NewClientBuilder().WithScheme(scheme).WithObjects(referencing, succeeded, otherTeam).Build(),
+		MatchRules: DefaultMatchRules(),
+	}
+
+	referenced, err := r.teamHasReferencingPods(context.Background(), "ml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !referenced {
+		t.Fatalf("expected team 'ml' to be referenced by the pending pod")
+	}
+
+	referenced, err = r.teamHasReferencingPods(context.Background(), "other")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if referenced {
+		t.Fatalf("expected team 'other' to have no referencing pods")
+	}
+}
+
+func TestTeamHasReferencingPods_EmptyTeamNeverMatches(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to register corev1 scheme: %v", err)
+	}
+
+	r := &NodePoolGCReconciler{
+		Client:     fake.NewClientBuilder().WithScheme(scheme).Build(),
+		MatchRules: DefaultMatchRules(),
+	}
+
+	referenced, err := r.teamHasReferencingPods(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if referenced {
+		t.Fatalf("expected an empty team value to never be considered referenced")
+	}
+}
+
+func TestIsDaemonSetPod(t *testing.T) {
+	daemonSetPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "node-exporter"}},
+		},
+	}
+	if !isDaemonSetPod(daemonSetPod) {
+		t.Fatalf("expected pod owned by a DaemonSet to be recognized as one")
+	}
+
+	deploymentPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-abc123"}},
+		},
+	}
+	if isDaemonSetPod(deploymentPod) {
+		t.Fatalf("expected pod owned by a ReplicaSet not to be recognized as a DaemonSet pod")
+	}
+}
+
+func TestLastReferencedTime(t *testing.T) {
+	nodePool := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	if _, stamped := lastReferencedTime(nodePool); stamped {
+		t.Fatalf("expected an unstamped NodePool to report stamped=false")
+	}
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	nodePool.SetAnnotations(map[string]string{lastReferencedAnnotation: now.Format(time.RFC3339)})
+
+	got, stamped := lastReferencedTime(nodePool)
+	if !stamped {
+		t.Fatalf("expected the stamped annotation to be recognized")
+	}
+	if !got.Equal(now) {
+		t.Fatalf("expected %v, got %v", now, got)
+	}
+
+	nodePool.SetAnnotations(map[string]string{lastReferencedAnnotation: "not-a-timestamp"})
+	if _, stamped := lastReferencedTime(nodePool); stamped {
+		t.Fatalf("expected a malformed annotation to report stamped=false")
+	}
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// nodePoolTeamIndexKey indexes unstructured NodePools by the value of their
+// 'provision-for-team' taint. Modeled on cluster-api's MachinePool-by-node
+// and MachinePool-by-providerID indexes, this lets findMatchingNodePool do
+// an indexed lookup instead of an O(N) scan of every NodePool.
+const nodePoolTeamIndexKey = "spec.template.spec.taints.provision-for-team"
+
+// nodePoolTeamValues extracts every 'provision-for-team' taint value off an
+// unstructured NodePool.
+func nodePoolTeamValues(u *unstructured.Unstructured) []string {
+	taints, found, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "taints")
+	if err != nil || !found {
+		return nil
+	}
+
+	var values []string
+	for _, taint := range taints {
+		taintMap, ok := taint.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, _, _ := unstructured.NestedString(taintMap, "key")
+		if key != "provision-for-team" {
+			continue
+		}
+		if value, found, _ := unstructured.NestedString(taintMap, "value"); found {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// indexNodePoolsByTeam registers the nodePoolTeamIndexKey field index.
+func (r *PodReconciler) indexNodePoolsByTeam(ctx context.Context, fieldIndexer client.FieldIndexer) error {
+	return fieldIndexer.IndexField(ctx, newNodePool(r.Resolver), nodePoolTeamIndexKey, func(obj client.Object) []string {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil
+		}
+		return nodePoolTeamValues(u)
+	})
+}
+
+// mapNodePoolToPendingPods maps a NodePool create/update/delete event back
+// to every pending Pod whose extracted MatchRule constraints the NodePool
+// satisfies, so pod reconciliation is driven by NodePool events rather than
+// a fixed RequeueAfter. This mirrors findMatchingNodePool's own matching
+// (extractPodConstraints + nodePoolSatisfiesConstraints) rather than the
+// single hardcoded 'provision-for-team' key, so a pod matched only through
+// affinity or toleration constraints is still re-enqueued once its NodePool
+// lands in the cache.
+func (r *PodReconciler) mapNodePoolToPendingPods(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods); err != nil {
+		logger.Error(err, "Failed to list pods for NodePool event", "nodePool", u.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+
+		constraints := extractPodConstraints(pod, r.MatchRules)
+		if len(constraints) == 0 {
+			continue
+		}
+
+		if nodePoolSatisfiesConstraints(u, constraints) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pod)})
+		}
+	}
+
+	return requests
+}
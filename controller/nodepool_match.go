@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// nodePoolSatisfiesConstraints reports whether every PodConstraint is
+// satisfied by the NodePool's structured spec.template.spec.taints and
+// spec.template.spec.requirements, ANDing across constraints the way
+// client-go's labels.Selector ANDs across a pod/RC selector's requirements.
+// Unlike a flat labels.Set match, a NodePool requirement carries an operator
+// and a list of values, so a constraint is satisfied by set membership
+// rather than flat key/value equality.
+func nodePoolSatisfiesConstraints(nodePool *unstructured.Unstructured, constraints []PodConstraint) bool {
+	taints, _, _ := unstructured.NestedSlice(nodePool.Object, "spec", "template", "spec", "taints")
+	requirements, _, _ := unstructured.NestedSlice(nodePool.Object, "spec", "template", "spec", "requirements")
+
+	for _, c := range constraints {
+		if !nodePoolHasTaint(taints, c) && !nodePoolHasRequirement(requirements, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// nodePoolHasTaint reports whether taints contains an entry exactly
+// matching the constraint's key/value.
+func nodePoolHasTaint(taints []interface{}, c PodConstraint) bool {
+	for _, t := range taints {
+		m, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, _, _ := unstructured.NestedString(m, "key")
+		value, _, _ := unstructured.NestedString(m, "value")
+		if key == c.Key && value == c.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// nodePoolHasRequirement reports whether requirements contains an entry
+// whose key matches the constraint and whose values include it.
+func nodePoolHasRequirement(requirements []interface{}, c PodConstraint) bool {
+	for _, r := range requirements {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, _, _ := unstructured.NestedString(m, "key")
+		if key != c.Key {
+			continue
+		}
+
+		values, _, _ := unstructured.NestedStringSlice(m, "values")
+		for _, v := range values {
+			if v == c.Value {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -5,142 +5,95 @@ import (
 	"fmt"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-// findMatchingNodePool searches for an existing NodePool matching the 'provision-for-team' taint
-func (r *PodReconciler) findMatchingNodePool(ctx context.Context, provisionForTeamValue string) (bool, error) {
+// findMatchingNodePool looks up an existing NodePool satisfying every given
+// PodConstraint. When a 'provision-for-team' constraint is present, the
+// nodePoolTeamIndexKey field index pre-filters candidates; every candidate
+// is then structurally matched against all constraints (not just team)
+// using nodePoolSatisfiesConstraints.
+func (r *PodReconciler) findMatchingNodePool(ctx context.Context, constraints []PodConstraint) (bool, error) {
 	logger := log.FromContext(ctx)
 
-	nodePools, err := r.listNodePools(ctx)
+	candidates, err := r.candidateNodePools(ctx, constraints)
 	if err != nil {
-		logger.Error(err, "Failed to list NodePools")
 		return false, err
 	}
 
-	for _, nodePool := range nodePools {
-		taints, foundTaints, err := unstructured.NestedSlice(nodePool.Object, "spec", "template", "spec", "taints")
-		if err != nil || !foundTaints {
-			logger.Error(err, "Error accessing 'taints' field in NodePool", "name", nodePool.GetName())
-			continue
-		}
-
-		for _, taint := range taints {
-			taintMap, ok := taint.(map[string]interface{})
-			if !ok {
-				logger.Error(fmt.Errorf("taint is not a map"), "Invalid taint format in NodePool", "name", nodePool.GetName())
-				continue
-			}
-
-			taintValue, foundValue, err := unstructured.NestedString(taintMap, "value")
-			if err != nil || !foundValue {
-				logger.Error(err, "Error accessing 'value' in taint", "name", nodePool.GetName())
-				continue
-			}
-
-			if taintValue == provisionForTeamValue {
-				logger.Info("Matching NodePool found", "NodePool", nodePool.GetName())
-				return true, nil
-			}
+	for i := range candidates {
+		if nodePoolSatisfiesConstraints(&candidates[i], constraints) {
+			logger.Info("Matching NodePool found", "NodePool", candidates[i].GetName())
+			return true, nil
 		}
 	}
 
-	logger.Info("No matching NodePool found for 'provision-for-team'", "value", provisionForTeamValue)
+	logger.Info("No matching NodePool found for pod constraints", "constraints", constraints)
 	return false, nil
 }
 
-// listNodePools lists NodePool objects using the dynamic client
-func (r *PodReconciler) listNodePools(ctx context.Context) ([]unstructured.Unstructured, error) {
+// candidateNodePools returns the NodePools worth structurally matching
+// against. A 'provision-for-team' constraint narrows the list via the team
+// index; otherwise every NodePool is a candidate.
+func (r *PodReconciler) candidateNodePools(ctx context.Context, constraints []PodConstraint) ([]unstructured.Unstructured, error) {
 	logger := log.FromContext(ctx)
+	nodePools := newNodePoolList(r.Resolver)
 
-	gvr := schema.GroupVersionResource{
-		Group:    "karpenter.sh",
-		Version:  "v1",
-		Resource: "nodepools",
+	if teamValue, ok := teamConstraintValue(constraints); ok {
+		if err := r.List(ctx, nodePools, client.MatchingFields{nodePoolTeamIndexKey: teamValue}); err != nil {
+			logger.Error(err, "Failed to list NodePools by team index", "value", teamValue)
+			return nil, err
+		}
+		return nodePools.Items, nil
 	}
 
-	if r.DynamicClient == nil {
-		err := fmt.Errorf("dynamic client is nil")
-		logger.Error(err, "Dynamic client is not initialized")
+	if err := r.List(ctx, nodePools); err != nil {
+		logger.Error(err, "Failed to list NodePools")
 		return nil, err
 	}
-
-	nodePoolsList, err := r.DynamicClient.Resource(gvr).Namespace("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list NodePools: %w", err)
-	}
-
-	return nodePoolsList.Items, nil
+	return nodePools.Items, nil
 }
 
-// createNodePool creates a new NodePool with the specified taint value
-func (r *PodReconciler) createNodePool(ctx context.Context, provisionForTeamValue string) error {
+// createNodePool renders and creates a NodePool satisfying the given pod
+// constraints, using the best-matching NodePoolTemplate for the primary
+// (team) constraint value, with any remaining constraints layered on as
+// extra requirements.
+func (r *PodReconciler) createNodePool(ctx context.Context, constraints []PodConstraint, podLabels map[string]string) error {
 	logger := log.FromContext(ctx)
 
-	gvr := schema.GroupVersionResource{
-		Group:    "karpenter.sh",
-		Version:  "v1",
-		Resource: "nodepools",
+	primary, ok := primaryConstraint(constraints)
+	if !ok {
+		return fmt.Errorf("no pod constraint available to provision a NodePool for")
 	}
+	teamValue := primary.Value
 
-	nodePoolName := fmt.Sprintf("nodepool-%s", provisionForTeamValue)
+	tpl, err := selectNodePoolTemplate(ctx, r.Client, teamValue, podLabels)
+	if err != nil {
+		return fmt.Errorf("failed to select NodePoolTemplate for team %q: %w", teamValue, err)
+	}
+	if tpl == nil {
+		// No explicit or "default" NodePoolTemplate permits this value. Unlike
+		// the admission webhook, the reconcile path has no policy to enforce
+		// here (that's done at admission time, if the webhook is enabled), so
+		// it falls back to the built-in template, same as a fresh install with
+		// no NodePoolTemplates provisioned at all.
+		logger.Info("No NodePoolTemplate matches, falling back to built-in default", "value", teamValue)
+		tpl = builtinDefaultNodePoolTemplate()
+	}
 
-	nodePool := &unstructured.Unstructured{
-		Object: map[string]interface{}{
-			"apiVersion": "karpenter.sh/v1",
-			"kind":       "NodePool",
-			"metadata": map[string]interface{}{
-				"name": nodePoolName,
-			},
-			"spec": map[string]interface{}{
-				"limits": map[string]interface{}{
-					"cpu":    "12000m",
-					"memory": "64Gi",
-				},
-				"template": map[string]interface{}{
-					"spec": map[string]interface{}{
-						"taints": []interface{}{
-							map[string]interface{}{
-								"key":    "provision-for-team",
-								"value":  provisionForTeamValue,
-								"effect": "NoSchedule",
-							},
-						},
-						"nodeClassRef": map[string]interface{}{
-							"group": "karpenter.k8s.aws",
-							"kind":  "EC2NodeClass",
-							"name":  "custom",
-						},
-						"requirements": []interface{}{
-							map[string]interface{}{
-								"key":      "karpenter.sh/capacity-type",
-								"operator": "In",
-								"values":   []interface{}{"spot"},
-							},
-						},
-						"expireAfter": "24h",
-					},
-				},
-				"disruption": map[string]interface{}{
-					"budgets": []interface{}{
-						map[string]interface{}{
-							"nodes": "10%",
-						},
-					},
-					"consolidateAfter":    "10m",
-					"consolidationPolicy": "WhenEmpty",
-				},
-			},
-		},
+	nodePool := renderNodePool(tpl, primary)
+	if err := appendConstraintRequirements(nodePool, constraints, primary); err != nil {
+		return fmt.Errorf("failed to apply pod constraints to NodePool: %w", err)
 	}
+	nodePoolName := nodePool.GetName()
 
-	_, err := r.DynamicClient.Resource(gvr).Namespace("").Create(ctx, nodePool, metav1.CreateOptions{})
+	_, err = r.DynamicClient.Resource(r.Resolver.Resource()).Namespace("").Create(ctx, nodePool, metav1.CreateOptions{})
 	if err != nil {
-		logger.Error(err, "Failed to create NodePool", "name", nodePoolName)
+		logger.Error(err, "Failed to create NodePool", "name", nodePoolName, "template", tpl.Name)
 		return fmt.Errorf("failed to create NodePool: %w", err)
 	}
 
-	logger.Info("Successfully created NodePool", "name", nodePoolName)
+	logger.Info("Successfully created NodePool", "name", nodePoolName, "template", tpl.Name)
 	return nil
 }
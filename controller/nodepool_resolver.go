@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	cacheddiscovery "k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// nodePoolGroupKind is the fixed karpenter.sh NodePool GroupKind. The served
+// version is resolved dynamically through NodePoolResolver below, so an
+// alpha -> v1 (or future) Karpenter API version bump never requires a code
+// change here.
+var nodePoolGroupKind = schema.GroupKind{Group: "karpenter.sh", Kind: "NodePool"}
+
+// DefaultNodePoolRediscoverInterval is how often NodePoolResolver forces a
+// fresh discovery pass, mirroring the generic garbage collector's periodic
+// ServerPreferredResources refresh so a newly installed Karpenter version is
+// picked up without a controller restart.
+const DefaultNodePoolRediscoverInterval = 10 * time.Minute
+
+// NodePoolResolver resolves the karpenter.sh NodePool GroupVersionResource
+// and GroupVersionKind through a cached, discovery-backed RESTMapper,
+// replacing the dynamic client's old pattern of a single hardcoded
+// "karpenter.sh/v1" GVR. This mirrors the dynamic.ClientPool + RESTMapper +
+// preferred-resources discovery approach the Kubernetes garbage collector
+// uses in place of a fixed GVR.
+type NodePoolResolver struct {
+	mapper meta.RESTMapper
+
+	mu  sync.RWMutex
+	gvr schema.GroupVersionResource
+	gvk schema.GroupVersionKind
+}
+
+// NewNodePoolResolver builds a NodePoolResolver backed by a
+// DeferredDiscoveryRESTMapper over cfg, resolving the NodePool
+// GroupVersionResource once before returning.
+func NewNodePoolResolver(cfg *rest.Config) (*NodePoolResolver, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cacheddiscovery.NewMemCacheClient(discoveryClient))
+
+	resolver := &NodePoolResolver{mapper: mapper}
+	if err := resolver.Refresh(); err != nil {
+		return nil, fmt.Errorf("failed to resolve NodePool resource: %w", err)
+	}
+
+	return resolver, nil
+}
+
+// Resource returns the currently resolved NodePool GroupVersionResource.
+func (r *NodePoolResolver) Resource() schema.GroupVersionResource {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.gvr
+}
+
+// Kind returns the currently resolved NodePool GroupVersionKind.
+func (r *NodePoolResolver) Kind() schema.GroupVersionKind {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.gvk
+}
+
+// Refresh forces fresh discovery and re-resolves the NodePool resource. It
+// also doubles as the NodePoolTemplate controller's schema-validation check:
+// an error here means karpenter.sh/NodePool isn't currently served.
+func (r *NodePoolResolver) Refresh() error {
+	if resettable, ok := r.mapper.(meta.ResettableRESTMapper); ok {
+		resettable.Reset()
+	}
+
+	mapping, err := r.mapper.RESTMapping(nodePoolGroupKind)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s via discovery: %w", nodePoolGroupKind, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gvr = mapping.Resource
+	r.gvk = mapping.GroupVersionKind
+	return nil
+}
+
+// Start implements manager.Runnable, periodically refreshing discovery for
+// as long as the manager runs.
+func (r *NodePoolResolver) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+	ticker := time.NewTicker(DefaultNodePoolRediscoverInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Refresh(); err != nil {
+				logger.Error(err, "Failed to refresh NodePool discovery")
+			}
+		}
+	}
+}
+
+// newNodePool returns an unstructured NodePool with its currently resolved
+// GVK set, suitable for use as the object passed to FieldIndexer.IndexField
+// or builder.Watches.
+func newNodePool(resolver *NodePoolResolver) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(resolver.Kind())
+	return u
+}
+
+// newNodePoolList returns an unstructured NodePoolList with its currently
+// resolved GVK set.
+func newNodePoolList(resolver *NodePoolResolver) *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	kind := resolver.Kind()
+	kind.Kind += "List"
+	list.SetGroupVersionKind(kind)
+	return list
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	nodepoolv1alpha1 "github.com/TrungHQ-02/nodepool-controller/api/v1alpha1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// NodePoolTemplateReconciler confirms karpenter.sh/NodePool is currently
+// served and reports the result on Status.Conditions. It does not validate
+// the NodePoolTemplate's own spec (nodeClassRef, requirement operators,
+// expireAfter, ...) against that schema; a malformed spec only surfaces once
+// renderNodePool's output is rejected by the API server at create time.
+type NodePoolTemplateReconciler struct {
+	client.Client
+
+	// Resolver resolves the karpenter.sh NodePool GVR/GVK through discovery.
+	// Its Refresh doubles as this controller's schema-validation check.
+	Resolver *NodePoolResolver
+}
+
+// NewNodePoolTemplateReconciler builds a NodePoolTemplateReconciler backed by
+// the shared NodePoolResolver.
+func NewNodePoolTemplateReconciler(mgr ctrl.Manager, resolver *NodePoolResolver) (*NodePoolTemplateReconciler, error) {
+	return &NodePoolTemplateReconciler{
+		Client:   mgr.GetClient(),
+		Resolver: resolver,
+	}, nil
+}
+
+// Reconcile confirms the karpenter.sh NodePool resource is served by the API
+// server and surfaces the result as a "SchemaAvailable" condition. This is a
+// discovery check only, not a validation of the template's own spec.
+func (r *NodePoolTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	tpl := &nodepoolv1alpha1.NodePoolTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, tpl); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get NodePoolTemplate", "nodePoolTemplate", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	condition := metav1.Condition{
+		Type:               "SchemaAvailable",
+		ObservedGeneration: tpl.Generation,
+		LastTransitionTime: metav1.Now(),
+	}
+
+	if err := r.validateAgainstDiscovery(); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SchemaUnavailable"
+		condition.Message = err.Error()
+		logger.Error(err, "NodePoolTemplate schema check failed", "nodePoolTemplate", req.NamespacedName)
+	} else {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "SchemaPresent"
+		condition.Message = fmt.Sprintf("%s NodePool resource is served by the API server; template spec itself is not validated against it", r.Resolver.Resource().GroupVersion())
+	}
+
+	if changed := apimeta.SetStatusCondition(&tpl.Status.Conditions, condition); changed {
+		if err := r.Status().Update(ctx, tpl); err != nil {
+			logger.Error(err, "Failed to update NodePoolTemplate status", "nodePoolTemplate", req.NamespacedName)
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// validateAgainstDiscovery confirms the karpenter.sh NodePool resource is
+// currently served. Its result only feeds the "SchemaAvailable" condition;
+// createNodePool renders templates whether or not the check passed. It forces a fresh discovery pass rather than
+// trusting the resolver's periodically-refreshed cache, so validation always
+// reflects the cluster's current state.
+func (r *NodePoolTemplateReconciler) validateAgainstDiscovery() error {
+	return r.Resolver.Refresh()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodePoolTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&nodepoolv1alpha1.NodePoolTemplate{}).
+		Complete(r)
+}
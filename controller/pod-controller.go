@@ -3,16 +3,13 @@ package controller
 import (
 	"context"
 	"fmt"
-	"time"
 
 	corev1 "k8s.io/api/core/v1"
-	// metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	// "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	//	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -21,18 +18,36 @@ type PodReconciler struct {
 	client.Client
 	Scheme        *runtime.Scheme
 	DynamicClient dynamic.Interface
+
+	// Resolver resolves the karpenter.sh NodePool GVR/GVK through discovery
+	// instead of a hardcoded version, so the NodePool API version can change
+	// without a code change here.
+	Resolver *NodePoolResolver
+
+	// MatchRules configures which pod nodeSelector/affinity/toleration keys
+	// are matched against NodePools. Loaded once at startup from the
+	// nodepool-controller-match-rules ConfigMap, falling back to
+	// DefaultMatchRules.
+	MatchRules []MatchRule
 }
 
-func NewPodReconciler(mgr ctrl.Manager) (*PodReconciler, error) {
+func NewPodReconciler(mgr ctrl.Manager, resolver *NodePoolResolver) (*PodReconciler, error) {
 	dynamicClient, err := dynamic.NewForConfig(mgr.GetConfig())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
+	matchRules, err := loadMatchRules(context.Background(), mgr.GetAPIReader())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load MatchRules: %w", err)
+	}
+
 	return &PodReconciler{
 		Client:        mgr.GetClient(),
 		Scheme:        mgr.GetScheme(),
 		DynamicClient: dynamicClient,
+		Resolver:      resolver,
+		MatchRules:    matchRules,
 	}, nil
 }
 
@@ -56,26 +71,27 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	if pod.Status.Phase == corev1.PodPending {
 		logger.Info("Pod is pending", "pod", req.NamespacedName)
 
-		provisionForTeamValue, exists := r.checkPodNodeSelector(ctx, pod)
+		constraints, exists := r.checkPodConstraints(ctx, pod)
 		if !exists {
 			return ctrl.Result{}, nil
 		}
 
-		matchingNodePoolFound, err := r.findMatchingNodePool(ctx, provisionForTeamValue)
+		matchingNodePoolFound, err := r.findMatchingNodePool(ctx, constraints)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
 
 		if !matchingNodePoolFound {
-			err := r.createNodePool(ctx, provisionForTeamValue)
+			err := r.createNodePool(ctx, constraints, pod.Labels)
 			if err != nil {
 				return ctrl.Result{}, err
 			}
-			// Optionally, you might want to requeue immediately to verify the NodePool creation
-			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			// No RequeueAfter: the NodePool watch re-enqueues this pod once
+			// the created NodePool lands in the cache.
+			return ctrl.Result{}, nil
 		}
 
-		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		return ctrl.Result{}, nil
 	}
 
 	return ctrl.Result{}, nil
@@ -83,7 +99,15 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := r.indexNodePoolsByTeam(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return fmt.Errorf("failed to index NodePools by team: %w", err)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Pod{}).
+		Watches(
+			newNodePool(r.Resolver),
+			handler.EnqueueRequestsFromMapFunc(r.mapNodePoolToPendingPods),
+		).
 		Complete(r)
 }
@@ -2,21 +2,24 @@ package controller
 
 import (
 	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-// checkPodNodeSelector checks for the 'provision-for-team' NodeSelector in the Pod spec
-func (r *PodReconciler) checkPodNodeSelector(ctx context.Context, pod *corev1.Pod) (string, bool) {
+// checkPodConstraints extracts the PodConstraints a pod requires of its
+// NodePool under the reconciler's configured MatchRules (nodeSelector,
+// required node affinity, and tolerations), generalizing the original
+// single-key 'provision-for-team' nodeSelector check.
+func (r *PodReconciler) checkPodConstraints(ctx context.Context, pod *corev1.Pod) ([]PodConstraint, bool) {
 	logger := log.FromContext(ctx)
 
-	nodeSelector := pod.Spec.NodeSelector
-	provisionForTeamValue, exists := nodeSelector["provision-for-team"]
-	if exists {
-		logger.Info("Found NodeSelector 'provision-for-team'", "value", provisionForTeamValue)
-	} else {
-		logger.Info("'provision-for-team' key not found in NodeSelector")
+	constraints := extractPodConstraints(pod, r.MatchRules)
+	if len(constraints) == 0 {
+		logger.Info("No configured MatchRule constraints found on pod")
+		return nil, false
 	}
 
-	return provisionForTeamValue, exists
+	logger.Info("Found pod constraints", "constraints", constraints)
+	return constraints, true
 }
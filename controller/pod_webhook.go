@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// PodAdmissionWebhook gates pending pods until a matching NodePool exists,
+// instead of letting a pod go Pending and waiting for PodReconciler to
+// notice. On every Pod CREATE it: synchronously creates a NodePool when no
+// match exists, optionally injects a matching 'provision-for-team'
+// toleration, and rejects pods requesting a team value no NodePoolTemplate
+// permits.
+type PodAdmissionWebhook struct {
+	// Reconciler supplies the MatchRules, NodePoolTemplate selection, and
+	// NodePool matching/creation logic the webhook reuses verbatim from the
+	// reconcile path, so the two stay consistent.
+	Reconciler *PodReconciler
+
+	// InjectToleration adds a matching toleration to admitted pods so they
+	// can schedule onto the NodePool being created.
+	InjectToleration bool
+
+	decoder *admission.Decoder
+}
+
+// NewPodAdmissionWebhook builds a PodAdmissionWebhook backed by an already
+// constructed PodReconciler.
+func NewPodAdmissionWebhook(mgr ctrl.Manager, reconciler *PodReconciler, injectToleration bool) *PodAdmissionWebhook {
+	return &PodAdmissionWebhook{
+		Reconciler:       reconciler,
+		InjectToleration: injectToleration,
+		decoder:          admission.NewDecoder(mgr.GetScheme()),
+	}
+}
+
+// Handle implements admission.Handler.
+func (w *PodAdmissionWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	logger := log.FromContext(ctx)
+
+	pod := &corev1.Pod{}
+	if err := w.decoder.Decode(req, pod); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	constraints := extractPodConstraints(pod, w.Reconciler.MatchRules)
+	if len(constraints) == 0 {
+		return admission.Allowed("no configured MatchRule constraints on pod")
+	}
+
+	teamValue, hasTeam := teamConstraintValue(constraints)
+	if hasTeam {
+		tpl, err := selectNodePoolTemplate(ctx, w.Reconciler.Client, teamValue, pod.Labels)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if tpl == nil {
+			return admission.Denied(fmt.Sprintf("no NodePoolTemplate permits team %q", teamValue))
+		}
+	}
+
+	matched, err := w.Reconciler.findMatchingNodePool(ctx, constraints)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if !matched {
+		logger.Info("No matching NodePool at admission time, creating synchronously", "pod", req.Name, "namespace", req.Namespace)
+		if err := w.Reconciler.createNodePool(ctx, constraints, pod.Labels); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	}
+
+	if !w.InjectToleration || !hasTeam {
+		return admission.Allowed("")
+	}
+
+	mutatedPod := pod.DeepCopy()
+	injectTeamToleration(mutatedPod, teamValue)
+
+	marshaled, err := json.Marshal(mutatedPod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// injectTeamToleration adds a toleration matching the
+// 'provision-for-team=<teamValue>:NoSchedule' taint, unless the pod already
+// has one.
+func injectTeamToleration(pod *corev1.Pod, teamValue string) {
+	for _, t := range pod.Spec.Tolerations {
+		if t.Key == provisionForTeamKey && t.Value == teamValue {
+			return
+		}
+	}
+
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+		Key:      provisionForTeamKey,
+		Operator: corev1.TolerationOpEqual,
+		Value:    teamValue,
+		Effect:   corev1.TaintEffectNoSchedule,
+	})
+}
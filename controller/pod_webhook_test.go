@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	nodepoolv1alpha1 "github.com/TrungHQ-02/nodepool-controller/api/v1alpha1"
+)
+
+func newTestWebhook(t *testing.T) *PodAdmissionWebhook {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to register corev1 scheme: %v", err)
+	}
+	if err := nodepoolv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to register nodepool.trunghq.io scheme: %v", err)
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	return &PodAdmissionWebhook{
+		Reconciler: &PodReconciler{
+			Client:     fakeClient,
+			MatchRules: DefaultMatchRules(),
+		},
+		decoder: admission.NewDecoder(scheme),
+	}
+}
+
+func admissionRequestFor(t *testing.T, pod *corev1.Pod) admission.Request {
+	t.Helper()
+
+	raw, err := json.Marshal(pod)
+	if err != nil {
+		t.Fatalf("failed to marshal pod: %v", err)
+	}
+
+	req := admission.Request{}
+	req.Object.Raw = raw
+	req.Name = pod.Name
+	req.Namespace = pod.Namespace
+	return req
+}
+
+func TestHandle_AllowsPodWithNoMatchRuleConstraints(t *testing.T) {
+	w := newTestWebhook(t)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "no-constraints"}}
+	resp := w.Handle(context.Background(), admissionRequestFor(t, pod))
+
+	if !resp.Allowed {
+		t.Fatalf("expected pod with no MatchRule constraints to be allowed, got: %+v", resp.Result)
+	}
+}
+
+func TestHandle_DeniesTeamNoTemplatePermits(t *testing.T) {
+	// No NodePoolTemplate exists in-cluster at all, so selectNodePoolTemplate
+	// has nothing to fall back to and must return nil, not
+	// builtinDefaultNodePoolTemplate, for the denial to actually fire.
+	w := newTestWebhook(t)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-pod"},
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{provisionForTeamKey: "ml"},
+		},
+	}
+	resp := w.Handle(context.Background(), admissionRequestFor(t, pod))
+
+	if resp.Allowed {
+		t.Fatalf("expected pod requesting an unpermitted team to be denied, got allowed")
+	}
+}
+
+func TestInjectTeamToleration(t *testing.T) {
+	pod := &corev1.Pod{}
+	injectTeamToleration(pod, "ml")
+	if len(pod.Spec.Tolerations) != 1 {
+		t.Fatalf("expected one toleration to be injected, got %d", len(pod.Spec.Tolerations))
+	}
+
+	// Injecting again for the same team must not duplicate the toleration.
+	injectTeamToleration(pod, "ml")
+	if len(pod.Spec.Tolerations) != 1 {
+		t.Fatalf("expected injectTeamToleration to be idempotent, got %d tolerations", len(pod.Spec.Tolerations))
+	}
+
+	tol := pod.Spec.Tolerations[0]
+	if tol.Key != provisionForTeamKey || tol.Value != "ml" || tol.Effect != corev1.TaintEffectNoSchedule {
+		t.Fatalf("unexpected toleration: %+v", tol)
+	}
+}
@@ -0,0 +1,266 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	nodepoolv1alpha1 "github.com/TrungHQ-02/nodepool-controller/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultNodePoolTemplateName is the cluster-wide fallback template used when
+// no team/selector-specific NodePoolTemplate matches.
+const defaultNodePoolTemplateName = "default"
+
+// builtinDefaultNodePoolTemplate reproduces the controller's pre-CRD
+// hardcoded NodePool spec. Callers apply it themselves when
+// selectNodePoolTemplate returns nil and they want a fresh install with no
+// NodePoolTemplates provisioned yet to still provision capacity out of the
+// box, same as before NodePoolTemplate existed.
+func builtinDefaultNodePoolTemplate() *nodepoolv1alpha1.NodePoolTemplate {
+	return &nodepoolv1alpha1.NodePoolTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: defaultNodePoolTemplateName},
+		Spec: nodepoolv1alpha1.NodePoolTemplateSpec{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("12000m"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			},
+			Requirements: []nodepoolv1alpha1.NodePoolRequirement{
+				{Key: "karpenter.sh/capacity-type", Operator: "In", Values: []string{"spot"}},
+			},
+			NodeClassRef: nodepoolv1alpha1.NodeClassReference{
+				Group: "karpenter.k8s.aws",
+				Kind:  "EC2NodeClass",
+				Name:  "custom",
+			},
+			ExpireAfter: "24h",
+			Disruption: nodepoolv1alpha1.DisruptionPolicy{
+				ConsolidationPolicy: "WhenEmpty",
+				ConsolidateAfter:    "10m",
+				Budgets:             []nodepoolv1alpha1.DisruptionBudget{{Nodes: "10%"}},
+			},
+		},
+	}
+}
+
+// selectNodePoolTemplate returns the best-matching NodePoolTemplate for a
+// team value and set of pod labels: among every template whose selector
+// matches (see templateMatches), the highest Spec.Priority wins, whether it
+// matched by TeamValues or by LabelSelector; the order among equal priorities
+// is not defined. The cluster-wide "default" template is returned if nothing
+// else matches. It returns (nil, nil), not
+// builtinDefaultNodePoolTemplate, when nothing permits teamValue/podLabels —
+// that's what lets the admission webhook tell "nothing configured yet" apart
+// from "explicitly not permitted". Callers that want the fresh-install
+// fallback (e.g. createNodePool) apply builtinDefaultNodePoolTemplate
+// themselves on a nil result.
+func selectNodePoolTemplate(ctx context.Context, c client.Client, teamValue string, podLabels map[string]string) (*nodepoolv1alpha1.NodePoolTemplate, error) {
+	var templates nodepoolv1alpha1.NodePoolTemplateList
+	if err := c.List(ctx, &templates); err != nil {
+		return nil, fmt.Errorf("failed to list NodePoolTemplates: %w", err)
+	}
+
+	var candidates []*nodepoolv1alpha1.NodePoolTemplate
+	var defaultTemplate *nodepoolv1alpha1.NodePoolTemplate
+	for i := range templates.Items {
+		tpl := &templates.Items[i]
+		if tpl.Name == defaultNodePoolTemplateName {
+			defaultTemplate = tpl
+		}
+
+		if !templateMatches(tpl, teamValue, podLabels) {
+			continue
+		}
+		candidates = append(candidates, tpl)
+	}
+
+	if len(candidates) == 0 {
+		return defaultTemplate, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Spec.Priority > candidates[j].Spec.Priority
+	})
+	return candidates[0], nil
+}
+
+// templateMatches reports whether a NodePoolTemplate's selector applies to
+// the given team value and pod labels. TeamValues and LabelSelector are both
+// evaluated when present; every configured constraint must match.
+func templateMatches(tpl *nodepoolv1alpha1.NodePoolTemplate, teamValue string, podLabels map[string]string) bool {
+	sel := tpl.Spec.Selector
+	if len(sel.TeamValues) == 0 && sel.LabelSelector == nil {
+		return false
+	}
+
+	if len(sel.TeamValues) > 0 {
+		matched := false
+		for _, v := range sel.TeamValues {
+			if v == teamValue {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if sel.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(sel.LabelSelector)
+		if err != nil {
+			return false
+		}
+		if !selector.Matches(labels.Set(podLabels)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// renderNodePool builds the unstructured karpenter.sh/v1 NodePool produced by
+// a NodePoolTemplate for a pod's primary constraint (see primaryConstraint):
+// the constraint's own key/value become the NodePool's access taint, so a
+// primary match on a non-team MatchRule (e.g. "architecture") taints the pool
+// with that real key instead of mislabeling it "provision-for-team".
+func renderNodePool(tpl *nodepoolv1alpha1.NodePoolTemplate, primary PodConstraint) *unstructured.Unstructured {
+	spec := tpl.Spec
+	teamValue := primary.Value
+
+	taints := []interface{}{
+		map[string]interface{}{
+			"key":    primary.Key,
+			"value":  primary.Value,
+			"effect": "NoSchedule",
+		},
+	}
+	for _, t := range spec.Taints {
+		taints = append(taints, map[string]interface{}{
+			"key":    t.Key,
+			"value":  t.Value,
+			"effect": string(t.Effect),
+		})
+	}
+
+	requirements := make([]interface{}, 0, len(spec.Requirements))
+	for _, req := range spec.Requirements {
+		values := make([]interface{}, 0, len(req.Values))
+		for _, v := range req.Values {
+			values = append(values, v)
+		}
+		requirements = append(requirements, map[string]interface{}{
+			"key":      req.Key,
+			"operator": req.Operator,
+			"values":   values,
+		})
+	}
+
+	budgets := make([]interface{}, 0, len(spec.Disruption.Budgets))
+	for _, b := range spec.Disruption.Budgets {
+		budget := map[string]interface{}{"nodes": b.Nodes}
+		if b.Schedule != "" {
+			budget["schedule"] = b.Schedule
+		}
+		budgets = append(budgets, budget)
+	}
+
+	limits := map[string]interface{}{}
+	for name, qty := range spec.Limits {
+		limits[name.String()] = qty.String()
+	}
+
+	templateSpec := map[string]interface{}{
+		"taints": taints,
+		"nodeClassRef": map[string]interface{}{
+			"group": spec.NodeClassRef.Group,
+			"kind":  spec.NodeClassRef.Kind,
+			"name":  spec.NodeClassRef.Name,
+		},
+		"requirements": requirements,
+	}
+	if spec.ExpireAfter != "" {
+		templateSpec["expireAfter"] = spec.ExpireAfter
+	}
+
+	template := map[string]interface{}{
+		"spec": templateSpec,
+	}
+	if len(spec.Labels) > 0 {
+		// spec.template.metadata.labels is a sibling of spec.template.spec on
+		// the real karpenter.sh/v1 NodePool schema, not a field inside
+		// NodeClaimSpec; node labels only take effect placed there.
+		template["metadata"] = map[string]interface{}{
+			"labels": spec.Labels,
+		}
+	}
+
+	nodePool := map[string]interface{}{
+		"apiVersion": "karpenter.sh/v1",
+		"kind":       "NodePool",
+		"metadata": map[string]interface{}{
+			"name": fmt.Sprintf("nodepool-%s", teamValue),
+			"annotations": map[string]interface{}{
+				"nodepool.trunghq.io/template": tpl.Name,
+				managedByAnnotation:            managedByValue,
+				teamAnnotation:                 teamValue,
+			},
+			"finalizers": []interface{}{gcFinalizer},
+		},
+		"spec": map[string]interface{}{
+			"limits":   limits,
+			"template": template,
+			"disruption": map[string]interface{}{
+				"consolidationPolicy": spec.Disruption.ConsolidationPolicy,
+				"consolidateAfter":    spec.Disruption.ConsolidateAfter,
+				"budgets":             budgets,
+			},
+		},
+	}
+
+	return &unstructured.Unstructured{Object: nodePool}
+}
+
+// appendConstraintRequirements adds an `In` `requirements` entry for every pod
+// constraint whose key the NodePool's requirements do not already name,
+// besides primary itself (already applied as the access taint by
+// renderNodePool). This lets a freshly created NodePool satisfy extra
+// constraints a MatchRule extracted, e.g. karpenter.sh/capacity-type, not
+// just the primary taint. A constraint whose key the template's requirements
+// already name is not added, even where that requirement's values exclude
+// the constraint's value; the NodePool then does not satisfy it.
+func appendConstraintRequirements(nodePool *unstructured.Unstructured, constraints []PodConstraint, primary PodConstraint) error {
+	requirements, _, err := unstructured.NestedSlice(nodePool.Object, "spec", "template", "spec", "requirements")
+	if err != nil {
+		return fmt.Errorf("failed to read existing requirements: %w", err)
+	}
+
+	existing := map[string]bool{}
+	for _, req := range requirements {
+		if m, ok := req.(map[string]interface{}); ok {
+			if key, _, _ := unstructured.NestedString(m, "key"); key != "" {
+				existing[key] = true
+			}
+		}
+	}
+
+	for _, c := range constraints {
+		if (c.Key == primary.Key && c.Value == primary.Value) || existing[c.Key] {
+			continue
+		}
+		requirements = append(requirements, map[string]interface{}{
+			"key":      c.Key,
+			"operator": "In",
+			"values":   []interface{}{c.Value},
+		})
+		existing[c.Key] = true
+	}
+
+	return unstructured.SetNestedSlice(nodePool.Object, requirements, "spec", "template", "spec", "requirements")
+}
@@ -4,14 +4,17 @@ import (
 	"os"
 	"time"
 
+	nodepoolv1alpha1 "github.com/TrungHQ-02/nodepool-controller/api/v1alpha1"
 	"github.com/TrungHQ-02/nodepool-controller/controller"
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/dynamic"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 var (
@@ -21,6 +24,7 @@ var (
 
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
+	_ = nodepoolv1alpha1.AddToScheme(scheme)
 }
 
 func main() {
@@ -29,12 +33,20 @@ func main() {
 	var syncPeriod time.Duration
 	var namespace string
 	var controllerNamespace string
+	var gcGracePeriod time.Duration
+	var gcDryRun bool
+	var enableAdmissionWebhook bool
+	var injectToleration bool
 
 	pflag.BoolVar(&enableLeaderElection, "enable-leader-election", false, "Enable leader election for controller manager, this will ensure there is only one active controller manager.")
 	pflag.DurationVar(&syncPeriod, "informer-re-sync-interval", 10*time.Second, "controller shared informer lister full re-sync period")
 	pflag.StringVar(&metricsAddr, "metrics-addr", ":38080", "The address the metric endpoint binds to.")
 	pflag.StringVar(&namespace, "namespace", "", "Namespace hehe to watch for resources, defaults to all namespaces")
 	pflag.StringVar(&controllerNamespace, "controller-namespace", "", "Namespace to run the terraform jobs")
+	pflag.DurationVar(&gcGracePeriod, "nodepool-gc-grace-period", controller.DefaultGCGracePeriod, "How long a managed NodePool's team must go unreferenced by any pod before it is garbage-collected")
+	pflag.BoolVar(&gcDryRun, "nodepool-gc-dry-run", false, "Log what the NodePool GC delete pipeline would do without cordoning nodes or deleting NodePools")
+	pflag.BoolVar(&enableAdmissionWebhook, "enable-admission-webhook", false, "Gate pending pods at admission time instead of waiting for PodReconciler to notice them. Defaults off: enabling it rejects every pod requesting a team with no matching NodePoolTemplate, so turn it on only once NodePoolTemplates (including a \"default\") are provisioned.")
+	pflag.BoolVar(&injectToleration, "webhook-inject-toleration", true, "Have the admission webhook inject a matching 'provision-for-team' toleration into admitted pods")
 	feature.DefaultMutableFeatureGate.AddFlag(pflag.CommandLine)
 	// embed klog
 	klog.InitFlags(nil)
@@ -53,7 +65,17 @@ func main() {
 		os.Exit(1)
 	}
 
-	reconciler, err := controller.NewPodReconciler(mgr)
+	resolver, err := controller.NewNodePoolResolver(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to resolve karpenter.sh NodePool resource")
+		os.Exit(1)
+	}
+	if err = mgr.Add(resolver); err != nil {
+		setupLog.Error(err, "unable to register NodePool resolver with manager")
+		os.Exit(1)
+	}
+
+	reconciler, err := controller.NewPodReconciler(mgr, resolver)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pod")
 		os.Exit(1)
@@ -64,6 +86,38 @@ func main() {
 		os.Exit(1)
 	}
 
+	templateReconciler, err := controller.NewNodePoolTemplateReconciler(mgr, resolver)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NodePoolTemplate")
+		os.Exit(1)
+	}
+
+	if err = templateReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller with manager", "controller", "NodePoolTemplate")
+		os.Exit(1)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create dynamic client")
+		os.Exit(1)
+	}
+
+	gcReconciler, err := controller.NewNodePoolGCReconciler(mgr, dynamicClient, resolver, gcGracePeriod, gcDryRun)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NodePoolGC")
+		os.Exit(1)
+	}
+	if err = gcReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to setup controller with manager", "controller", "NodePoolGC")
+		os.Exit(1)
+	}
+
+	if enableAdmissionWebhook {
+		podWebhook := controller.NewPodAdmissionWebhook(mgr, reconciler, injectToleration)
+		mgr.GetWebhookServer().Register("/mutate-validate-v1-pod", &admission.Webhook{Handler: podWebhook})
+	}
+
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)